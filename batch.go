@@ -0,0 +1,174 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+)
+
+// maxBatchSize is the maximum number of messages Postmark accepts in a
+// single call to email/batchWithTemplates.
+const maxBatchSize = 500
+
+// batchConcurrency bounds how many sub-requests SendBatchWithTemplates will
+// have in flight at once.
+const batchConcurrency = 5
+
+// Attachment represents a file attached to an outgoing message.
+type Attachment struct {
+	Name        string `json:"name"`
+	Content     string `json:"content"`
+	ContentType string `json:"contentType"`
+	ContentID   string `json:"contentID,omitempty"`
+}
+
+// TemplatedMessage is a single recipient's worth of input to
+// SendBatchWithTemplates.
+type TemplatedMessage struct {
+	From          string                 `json:"from"`
+	To            string                 `json:"to"`
+	TemplateId    int64                  `json:"templateId,omitempty"`
+	TemplateAlias string                 `json:"templateAlias,omitempty"`
+	TemplateModel map[string]interface{} `json:"templateModel,omitempty"`
+	MessageStream string                 `json:"messageStream,omitempty"`
+	Tag           string                 `json:"tag,omitempty"`
+	Metadata      map[string]string      `json:"metadata,omitempty"`
+	Attachments   []Attachment           `json:"attachments,omitempty"`
+}
+
+// SendResult is Postmark's per-recipient response to a batch send.
+type SendResult struct {
+	MessageID   string `json:"messageID,omitempty"`
+	ErrorCode   int    `json:"errorCode"`
+	Message     string `json:"message"`
+	SubmittedAt string `json:"submittedAt,omitempty"`
+}
+
+// batchWithTemplatesRequest is the envelope email/batchWithTemplates expects.
+type batchWithTemplatesRequest struct {
+	Messages []TemplatedMessage `json:"messages"`
+}
+
+// SendBatchWithTemplates sends templated messages via Postmark's
+// email/batchWithTemplates endpoint. Input is chunked into sub-requests of
+// at most maxBatchSize messages, those chunks are sent concurrently with a
+// bounded worker pool, and the returned slice preserves the order of the
+// input messages.
+//
+// The returned slice is always fully populated, one SendResult per input
+// message, even when err is non-nil: messages in chunks that failed outright
+// (request error, non-200 response) get a synthetic SendResult with
+// ErrorCode -1 and Message describing the failure, and any chunk response
+// that Postmark sends back short gets the same treatment for its missing
+// trailing messages. Callers that only care whether everything went out can
+// check err; callers that sent a large batch and want to know which
+// recipients actually succeeded should inspect the slice regardless of err.
+func SendBatchWithTemplates(apiToken string, messages []TemplatedMessage) ([]SendResult, error) {
+	if len(messages) == 0 {
+		return nil, nil
+	}
+
+	type chunkJob struct {
+		offset   int
+		messages []TemplatedMessage
+	}
+
+	var jobs []chunkJob
+	for offset := 0; offset < len(messages); offset += maxBatchSize {
+		end := offset + maxBatchSize
+		if end > len(messages) {
+			end = len(messages)
+		}
+		jobs = append(jobs, chunkJob{offset: offset, messages: messages[offset:end]})
+	}
+
+	results := make([]SendResult, len(messages))
+	errs := make([]error, len(jobs))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, batchConcurrency)
+
+	for i, job := range jobs {
+		wg.Add(1)
+		go func(i int, job chunkJob) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			chunkResults, err := sendBatchChunk(apiToken, job.messages)
+			if err != nil {
+				errs[i] = err
+				fillMissingResults(results[job.offset:job.offset+len(job.messages)], err)
+				return
+			}
+			n := copy(results[job.offset:job.offset+len(job.messages)], chunkResults)
+			if n < len(job.messages) {
+				err := fmt.Errorf("postmark returned %d results for a chunk of %d messages", len(chunkResults), len(job.messages))
+				errs[i] = err
+				fillMissingResults(results[job.offset+n:job.offset+len(job.messages)], err)
+			}
+		}(i, job)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return results, err
+		}
+	}
+
+	return results, nil
+}
+
+// fillMissingResults stamps every SendResult in dst with a synthetic
+// ErrorCode and err's message, for messages SendBatchWithTemplates can't
+// report a real Postmark result for.
+func fillMissingResults(dst []SendResult, err error) {
+	for i := range dst {
+		dst[i] = SendResult{ErrorCode: -1, Message: err.Error()}
+	}
+}
+
+// batchURL is the endpoint sendBatchChunk posts to. It's a var rather than a
+// const so tests can point it at an httptest.Server.
+var batchURL = "https://api.postmarkapp.com/email/batchWithTemplates"
+
+// sendBatchChunk sends a single sub-request of at most maxBatchSize messages.
+func sendBatchChunk(apiToken string, messages []TemplatedMessage) ([]SendResult, error) {
+	jsonData, err := json.Marshal(batchWithTemplatesRequest{Messages: messages})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal batch messages: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", batchURL, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Postmark-Server-Token", apiToken)
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := ioutil.ReadAll(resp.Body)
+		bodyString := string(bodyBytes)
+		return nil, fmt.Errorf("unexpected status code: %d, response: %s", resp.StatusCode, bodyString)
+	}
+
+	var results []SendResult
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// withBatchServer points batchURL at an httptest.Server running handler for
+// the duration of the test, restoring the real endpoint afterward.
+func withBatchServer(t *testing.T, handler http.HandlerFunc) {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	original := batchURL
+	batchURL = server.URL
+	t.Cleanup(func() { batchURL = original })
+}
+
+func TestSendBatchWithTemplatesChunksAndPreservesOrder(t *testing.T) {
+	var gotChunkSizes []int
+	withBatchServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var req batchWithTemplatesRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		gotChunkSizes = append(gotChunkSizes, len(req.Messages))
+
+		results := make([]SendResult, len(req.Messages))
+		for i, m := range req.Messages {
+			results[i] = SendResult{MessageID: m.To}
+		}
+		json.NewEncoder(w).Encode(results)
+	})
+
+	messages := make([]TemplatedMessage, maxBatchSize+1)
+	for i := range messages {
+		messages[i] = TemplatedMessage{To: string(rune('a' + i%26))}
+	}
+
+	results, err := SendBatchWithTemplates("test-token", messages)
+	if err != nil {
+		t.Fatalf("SendBatchWithTemplates returned error: %v", err)
+	}
+	if len(results) != len(messages) {
+		t.Fatalf("len(results) = %d, want %d", len(results), len(messages))
+	}
+	for i, r := range results {
+		if r.MessageID != messages[i].To {
+			t.Errorf("results[%d].MessageID = %q, want %q (order not preserved)", i, r.MessageID, messages[i].To)
+		}
+	}
+	if len(gotChunkSizes) != 2 {
+		t.Fatalf("server saw %d chunks, want 2", len(gotChunkSizes))
+	}
+}
+
+func TestSendBatchWithTemplatesShortResponse(t *testing.T) {
+	withBatchServer(t, func(w http.ResponseWriter, r *http.Request) {
+		// Postmark returns fewer results than messages sent.
+		json.NewEncoder(w).Encode([]SendResult{{MessageID: "only-one"}})
+	})
+
+	messages := []TemplatedMessage{{To: "a@example.com"}, {To: "b@example.com"}}
+	results, err := SendBatchWithTemplates("test-token", messages)
+	if err == nil {
+		t.Fatal("expected an error for a short chunk response, got nil")
+	}
+	if len(results) != len(messages) {
+		t.Fatalf("len(results) = %d, want %d", len(results), len(messages))
+	}
+	if results[0].MessageID != "only-one" {
+		t.Errorf("results[0] = %+v, want the real result", results[0])
+	}
+	if results[1].ErrorCode == 0 {
+		t.Errorf("results[1] = %+v, want a synthesized error result", results[1])
+	}
+}
+
+func TestSendBatchWithTemplatesChunkFailurePreservesOtherResults(t *testing.T) {
+	withBatchServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var req batchWithTemplatesRequest
+		json.NewDecoder(r.Body).Decode(&req)
+
+		// Fail only the full-size chunk, so which chunk the server sees
+		// first doesn't matter.
+		if len(req.Messages) == maxBatchSize {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte("boom"))
+			return
+		}
+		results := make([]SendResult, len(req.Messages))
+		for i, m := range req.Messages {
+			results[i] = SendResult{MessageID: m.To}
+		}
+		json.NewEncoder(w).Encode(results)
+	})
+
+	messages := make([]TemplatedMessage, maxBatchSize+1)
+	for i := range messages {
+		messages[i] = TemplatedMessage{To: string(rune('a' + i%26))}
+	}
+
+	results, err := SendBatchWithTemplates("test-token", messages)
+	if err == nil {
+		t.Fatal("expected an error from the failing chunk, got nil")
+	}
+	if len(results) != len(messages) {
+		t.Fatalf("len(results) = %d, want %d", len(results), len(messages))
+	}
+	// The last message was its own one-message chunk, which didn't fail.
+	if results[len(messages)-1].ErrorCode != 0 {
+		t.Errorf("results[%d] = %+v, want a successful result from the chunk that didn't fail", len(messages)-1, results[len(messages)-1])
+	}
+	// The full-size chunk failed outright, so its messages get synthesized
+	// error results instead of being left zero-valued.
+	if results[0].ErrorCode == 0 {
+		t.Errorf("results[0] = %+v, want a synthesized error result", results[0])
+	}
+}
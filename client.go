@@ -0,0 +1,320 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// defaultBaseURL is the root of the Postmark API.
+const defaultBaseURL = "https://api.postmarkapp.com"
+
+// defaultMaxRetries is how many times Client retries a 429 or idempotent
+// 5xx response before giving up.
+const defaultMaxRetries = 3
+
+// Client is a Postmark API client. Unlike the package-level functions it
+// accepts a context.Context on every call, retries 429s and idempotent
+// 5xx responses with exponential backoff, and allows the underlying
+// *http.Client and base URL to be swapped out for testing.
+type Client struct {
+	serverToken string
+	httpClient  *http.Client
+	baseURL     string
+	maxRetries  int
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient overrides the *http.Client used to make requests, e.g. to
+// inject a proxy or a client with custom timeouts.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithBaseURL overrides the Postmark API root, e.g. to point at an
+// httptest.Server in tests.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = baseURL
+	}
+}
+
+// WithMaxRetries overrides how many times a 429 or idempotent 5xx response
+// is retried before Client gives up and returns an error.
+func WithMaxRetries(maxRetries int) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+	}
+}
+
+// NewClient builds a Client for the given Postmark server token.
+func NewClient(serverToken string, opts ...Option) *Client {
+	c := &Client{
+		serverToken: serverToken,
+		httpClient:  &http.Client{},
+		baseURL:     defaultBaseURL,
+		maxRetries:  defaultMaxRetries,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// do sends req, retrying 429 responses (honoring Postmark's
+// X-Postmark-RateLimit-* headers) and idempotent 5xx responses with
+// exponential backoff and jitter. body is re-sent verbatim on each retry.
+func (c *Client) do(ctx context.Context, method, path string, body []byte) (*http.Response, error) {
+	var resp *http.Response
+	for attempt := 0; ; attempt++ {
+		var reader *bytes.Reader
+		if body != nil {
+			reader = bytes.NewReader(body)
+		} else {
+			reader = bytes.NewReader(nil)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("Accept", "application/json")
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		req.Header.Set("X-Postmark-Server-Token", c.serverToken)
+
+		resp, err = c.httpClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to send request: %w", err)
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests && attempt < c.maxRetries {
+			wait := rateLimitWait(resp.Header, attempt)
+			resp.Body.Close()
+			if !sleepCtx(ctx, wait) {
+				return nil, ctx.Err()
+			}
+			continue
+		}
+
+		if resp.StatusCode >= 500 && resp.StatusCode < 600 && isIdempotent(method) && attempt < c.maxRetries {
+			resp.Body.Close()
+			if !sleepCtx(ctx, backoffWait(attempt)) {
+				return nil, ctx.Err()
+			}
+			continue
+		}
+
+		return resp, nil
+	}
+}
+
+// isIdempotent reports whether a request can be safely retried.
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// rateLimitWait computes how long to sleep before retrying a 429,
+// preferring Postmark's X-Postmark-RateLimit-Retry-After header and
+// falling back to exponential backoff.
+func rateLimitWait(header http.Header, attempt int) time.Duration {
+	if retryAfter := header.Get("X-Postmark-RateLimit-Retry-After"); retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil {
+			return time.Duration(seconds) * time.Second
+		}
+	}
+	return backoffWait(attempt)
+}
+
+// backoffWait returns an exponential backoff duration with jitter for the
+// given (zero-indexed) retry attempt.
+func backoffWait(attempt int) time.Duration {
+	base := time.Duration(1<<uint(attempt)) * 200 * time.Millisecond
+	jitter := time.Duration(rand.Int63n(int64(base) + 1))
+	return base + jitter
+}
+
+// sleepCtx sleeps for d or until ctx is done, whichever comes first. It
+// reports whether the sleep completed normally.
+func sleepCtx(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// CreateTemplate creates a new template in Postmark.
+func (c *Client) CreateTemplate(ctx context.Context, template Template) (int64, error) {
+	jsonData, err := json.Marshal(template)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal template: %w", err)
+	}
+
+	resp, err := c.do(ctx, "POST", "/templates", jsonData)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := ioutil.ReadAll(resp.Body)
+		bodyString := string(bodyBytes)
+		return 0, fmt.Errorf("unexpected status code: %d, response: %s", resp.StatusCode, bodyString)
+	}
+
+	var postmarkResponse PostmarkResponse
+	if err := json.NewDecoder(resp.Body).Decode(&postmarkResponse); err != nil {
+		return 0, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if postmarkResponse.ErrorCode != 0 {
+		return 0, fmt.Errorf("failed to create template: %s", postmarkResponse.Message)
+	}
+
+	return postmarkResponse.TemplateID, nil
+}
+
+// GetTemplate retrieves a template by its ID.
+func (c *Client) GetTemplate(ctx context.Context, templateID int64) (*Template, error) {
+	resp, err := c.do(ctx, "GET", fmt.Sprintf("/templates/%d", templateID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := ioutil.ReadAll(resp.Body)
+		bodyString := string(bodyBytes)
+		return nil, fmt.Errorf("unexpected status code: %d, response: %s", resp.StatusCode, bodyString)
+	}
+
+	var template Template
+	if err := json.NewDecoder(resp.Body).Decode(&template); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &template, nil
+}
+
+// UpdateTemplate updates an existing template in Postmark.
+func (c *Client) UpdateTemplate(ctx context.Context, template Template) error {
+	jsonData, err := json.Marshal(template)
+	if err != nil {
+		return fmt.Errorf("failed to marshal template: %w", err)
+	}
+
+	resp, err := c.do(ctx, "PUT", fmt.Sprintf("/templates/%d", template.TemplateID), jsonData)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := ioutil.ReadAll(resp.Body)
+		bodyString := string(bodyBytes)
+		return fmt.Errorf("unexpected status code: %d, response: %s", resp.StatusCode, bodyString)
+	}
+
+	var postmarkResponse PostmarkResponse
+	if err := json.NewDecoder(resp.Body).Decode(&postmarkResponse); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if postmarkResponse.ErrorCode != 0 {
+		return fmt.Errorf("failed to update template: %s", postmarkResponse.Message)
+	}
+
+	return nil
+}
+
+// DeleteTemplate deletes a template in Postmark.
+func (c *Client) DeleteTemplate(ctx context.Context, templateID int64) error {
+	resp, err := c.do(ctx, "DELETE", fmt.Sprintf("/templates/%d", templateID), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := ioutil.ReadAll(resp.Body)
+		bodyString := string(bodyBytes)
+		return fmt.Errorf("unexpected status code: %d, response: %s", resp.StatusCode, bodyString)
+	}
+
+	return nil
+}
+
+// GetTemplates retrieves a list of all templates in Postmark.
+func (c *Client) GetTemplates(ctx context.Context, offset, count int) ([]Template, error) {
+	return c.getTemplatesByType(ctx, offset, count, "")
+}
+
+// getTemplatesByType lists templates, optionally filtering server-side by
+// TemplateType (one of "Standard" or "Layout"). An empty templateType
+// matches every template, mirroring Postmark's "All" default.
+func (c *Client) getTemplatesByType(ctx context.Context, offset, count int, templateType string) ([]Template, error) {
+	path := fmt.Sprintf("/templates?offset=%d&count=%d", offset, count)
+	if templateType != "" {
+		path += "&TemplateType=" + templateType
+	}
+	resp, err := c.do(ctx, "GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := ioutil.ReadAll(resp.Body)
+		bodyString := string(bodyBytes)
+		return nil, fmt.Errorf("unexpected status code: %d, response: %s", resp.StatusCode, bodyString)
+	}
+
+	var templatesResponse TemplatesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&templatesResponse); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return templatesResponse.Templates, nil
+}
+
+// ValidateTemplate validates a template in Postmark.
+func (c *Client) ValidateTemplate(ctx context.Context, template Template) error {
+	jsonData, err := json.Marshal(template)
+	if err != nil {
+		return fmt.Errorf("failed to marshal template: %w", err)
+	}
+
+	resp, err := c.do(ctx, "POST", "/templates/validate", jsonData)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := ioutil.ReadAll(resp.Body)
+		bodyString := string(bodyBytes)
+		return fmt.Errorf("unexpected status code: %d, response: %s", resp.StatusCode, bodyString)
+	}
+
+	return nil
+}
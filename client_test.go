@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientGetTemplateRoundTrip(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("X-Postmark-Server-Token"); got != "test-token" {
+			t.Errorf("X-Postmark-Server-Token = %q, want %q", got, "test-token")
+		}
+		if r.URL.Path != "/templates/42" {
+			t.Errorf("path = %q, want %q", r.URL.Path, "/templates/42")
+		}
+		json.NewEncoder(w).Encode(Template{TemplateID: 42, Name: "Welcome"})
+	}))
+	defer server.Close()
+
+	c := NewClient("test-token", WithBaseURL(server.URL))
+	template, err := c.GetTemplate(context.Background(), 42)
+	if err != nil {
+		t.Fatalf("GetTemplate returned error: %v", err)
+	}
+	if template.TemplateID != 42 || template.Name != "Welcome" {
+		t.Errorf("template = %+v, want {TemplateID: 42, Name: Welcome}", template)
+	}
+}
+
+func TestClientRetriesOn429(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.Header().Set("X-Postmark-RateLimit-Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		json.NewEncoder(w).Encode(Template{TemplateID: 1})
+	}))
+	defer server.Close()
+
+	c := NewClient("test-token", WithBaseURL(server.URL), WithMaxRetries(1))
+	if _, err := c.GetTemplate(context.Background(), 1); err != nil {
+		t.Fatalf("GetTemplate returned error: %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("server saw %d requests, want 2 (one 429 then a retry)", requests)
+	}
+}
+
+func TestClientPreviewHitsValidateEndpoint(t *testing.T) {
+	var gotPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			json.NewEncoder(w).Encode(Template{TemplateID: 7, Subject: "Hi {{name}}"})
+		case http.MethodPost:
+			gotPath = r.URL.Path
+			json.NewEncoder(w).Encode(ValidationResponse{AllContentIsValid: true})
+		}
+	}))
+	defer server.Close()
+
+	c := NewClient("test-token", WithBaseURL(server.URL))
+	if _, err := c.Preview(context.Background(), 7, map[string]interface{}{"name": "Alice"}); err != nil {
+		t.Fatalf("Preview returned error: %v", err)
+	}
+	if gotPath != "/templates/validate" {
+		t.Errorf("Preview posted to %q, want %q", gotPath, "/templates/validate")
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"fmt"
+)
+
+// ListLayouts retrieves every template on the server whose TemplateType is
+// Layout, so callers can discover available layouts to compose content
+// templates with.
+func ListLayouts(apiToken string) ([]Template, error) {
+	return NewClient(apiToken).ListLayouts(context.Background())
+}
+
+// ListLayouts retrieves every template whose TemplateType is Layout, using
+// Postmark's server-side TemplateType filter rather than paging through and
+// filtering every template client-side.
+func (c *Client) ListLayouts(ctx context.Context) ([]Template, error) {
+	const pageSize = 100
+	var layouts []Template
+	for offset := 0; ; offset += pageSize {
+		page, err := c.getTemplatesByType(ctx, offset, pageSize, TemplateTypeLayout)
+		if err != nil {
+			return nil, err
+		}
+		layouts = append(layouts, page...)
+		if len(page) < pageSize {
+			return layouts, nil
+		}
+	}
+}
+
+// PushTemplateWithLayout fetches the template identified by templateAlias,
+// sets its LayoutTemplate to layoutAlias, and pushes the change back to
+// Postmark, composing the template with the named layout's shared
+// header/footer.
+func PushTemplateWithLayout(apiToken, templateAlias, layoutAlias string) error {
+	return NewClient(apiToken).PushTemplateWithLayout(context.Background(), templateAlias, layoutAlias)
+}
+
+// PushTemplateWithLayout fetches the template identified by templateAlias,
+// sets its LayoutTemplate to layoutAlias, and pushes the change back to
+// Postmark.
+func (c *Client) PushTemplateWithLayout(ctx context.Context, templateAlias, layoutAlias string) error {
+	template, err := c.getTemplateByAlias(ctx, templateAlias)
+	if err != nil {
+		return err
+	}
+
+	template.LayoutTemplate = layoutAlias
+	return c.UpdateTemplate(ctx, *template)
+}
+
+// getTemplateByAlias looks up a template by alias, since Postmark's
+// templates API only supports fetching by numeric TemplateID directly.
+func (c *Client) getTemplateByAlias(ctx context.Context, alias string) (*Template, error) {
+	all, err := c.allTemplates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range all {
+		if t.Alias == alias {
+			return c.GetTemplate(ctx, t.TemplateID)
+		}
+	}
+	return nil, fmt.Errorf("no template found with alias %q", alias)
+}
+
+// allTemplates pages through GetTemplates until it has seen every template
+// on the server.
+func (c *Client) allTemplates(ctx context.Context) ([]Template, error) {
+	const pageSize = 100
+	var all []Template
+	for offset := 0; ; offset += pageSize {
+		page, err := c.GetTemplates(ctx, offset, pageSize)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page...)
+		if len(page) < pageSize {
+			return all, nil
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientListLayoutsFiltersServerSide(t *testing.T) {
+	var gotQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQuery = r.URL.RawQuery
+		json.NewEncoder(w).Encode(TemplatesResponse{
+			Templates: []Template{
+				{TemplateID: 1, Name: "Main Layout", TemplateType: TemplateTypeLayout},
+			},
+			TotalCount: 1,
+		})
+	}))
+	defer server.Close()
+
+	c := NewClient("test-token", WithBaseURL(server.URL))
+	layouts, err := c.ListLayouts(context.Background())
+	if err != nil {
+		t.Fatalf("ListLayouts returned error: %v", err)
+	}
+	if len(layouts) != 1 || layouts[0].Name != "Main Layout" {
+		t.Errorf("layouts = %+v, want one layout named Main Layout", layouts)
+	}
+	if gotQuery != "offset=0&count=100&TemplateType=Layout" {
+		t.Errorf("query = %q, want offset=0&count=100&TemplateType=Layout (server-side filter)", gotQuery)
+	}
+}
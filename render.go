@@ -0,0 +1,388 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ValidationError describes a single Mustachio problem Postmark found while
+// validating a template.
+type ValidationError struct {
+	Message           string `json:"message"`
+	Line              int    `json:"line,omitempty"`
+	CharacterPosition int    `json:"characterPosition,omitempty"`
+}
+
+// ValidationItem is Postmark's validation result for a single template
+// field (subject, HTML body, or text body).
+type ValidationItem struct {
+	ContentIsValid   bool              `json:"contentIsValid"`
+	ValidationErrors []ValidationError `json:"validationErrors,omitempty"`
+	RenderedContent  string            `json:"renderedContent"`
+}
+
+// ValidationResponse is Postmark's response from /templates/validate.
+type ValidationResponse struct {
+	AllContentIsValid      bool                   `json:"allContentIsValid"`
+	Subject                ValidationItem         `json:"subject"`
+	HtmlBody               ValidationItem         `json:"htmlBody"`
+	TextBody               ValidationItem         `json:"textBody"`
+	SuggestedTemplateModel map[string]interface{} `json:"suggestedTemplateModel,omitempty"`
+}
+
+// previewRequest is the body /templates/validate expects.
+type previewRequest struct {
+	Subject          string                 `json:"subject"`
+	HtmlBody         string                 `json:"htmlBody"`
+	TextBody         string                 `json:"textBody"`
+	TestRenderModel  map[string]interface{} `json:"testRenderModel"`
+	InlineCSSForHTML bool                   `json:"inlineCssForHtmlTestRenderModel"`
+}
+
+// Preview renders templateID against model on Postmark's servers and
+// returns the rendered subject/HTML/text along with a suggested template
+// model and any per-field validation errors. No separate list of unused
+// model variables is returned. Unlike
+// RenderTemplate it requires a network round-trip, since Postmark's
+// Mustachio implementation is the source of truth for what it accepts.
+func Preview(apiToken string, templateID int64, model map[string]interface{}) (*ValidationResponse, error) {
+	return NewClient(apiToken).Preview(context.Background(), templateID, model)
+}
+
+// Preview renders templateID against model via Postmark's
+// /templates/validate endpoint.
+func (c *Client) Preview(ctx context.Context, templateID int64, model map[string]interface{}) (*ValidationResponse, error) {
+	template, err := c.GetTemplate(ctx, templateID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch template: %w", err)
+	}
+
+	jsonData, err := json.Marshal(previewRequest{
+		Subject:          template.Subject,
+		HtmlBody:         template.HtmlBody,
+		TextBody:         template.TextBody,
+		TestRenderModel:  model,
+		InlineCSSForHTML: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal preview request: %w", err)
+	}
+
+	resp, err := c.do(ctx, "POST", "/templates/validate", jsonData)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		bodyBytes, _ := ioutil.ReadAll(resp.Body)
+		return nil, fmt.Errorf("unexpected status code: %d, response: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var validation ValidationResponse
+	if err := json.NewDecoder(resp.Body).Decode(&validation); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &validation, nil
+}
+
+// RenderTemplate renders template's subject, HTML body, and text body
+// against model entirely locally, using Postmark's Mustachio-compatible
+// syntax: {{variable}}, {{#section}}...{{/section}} for truthy values and
+// iteration, {{^inverted}}...{{/inverted}}, {{{unescaped}}}, and dotted-path
+// access into nested maps and structs. This lets tests exercise template
+// rendering without a network round-trip.
+//
+// It does not compose template.LayoutTemplate; a template using a layout
+// will render its own content only, not the combined output Postmark's API
+// would send. Use Preview for a faithful, layout-aware render.
+func RenderTemplate(template Template, model map[string]interface{}) (subject, html, text string, err error) {
+	if subject, err = renderMustache(template.Subject, model); err != nil {
+		return "", "", "", fmt.Errorf("failed to render subject: %w", err)
+	}
+	if html, err = renderMustache(template.HtmlBody, model); err != nil {
+		return "", "", "", fmt.Errorf("failed to render htmlBody: %w", err)
+	}
+	if text, err = renderMustache(template.TextBody, model); err != nil {
+		return "", "", "", fmt.Errorf("failed to render textBody: %w", err)
+	}
+	return subject, html, text, nil
+}
+
+// TemplateModelSchema walks a template body and returns the sorted, deduped
+// set of variables it references, e.g. for a CI check that a change to a
+// model hasn't broken an existing template's expected inputs. Variables
+// referenced inside a {{#section}}/{{^section}} block are scoped under their
+// parent as "section.child", since they describe fields of the section's
+// context rather than top-level model inputs.
+func TemplateModelSchema(body string) ([]string, error) {
+	nodes, _, err := parseMustache(body)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	var walk func(nodes []mustacheNode, prefix string)
+	walk = func(nodes []mustacheNode, prefix string) {
+		for _, n := range nodes {
+			switch n.kind {
+			case nodeVariable, nodeUnescaped:
+				seen[prefix+n.key] = true
+			case nodeSection, nodeInverted:
+				seen[prefix+n.key] = true
+				walk(n.children, prefix+n.key+".")
+			}
+		}
+	}
+	walk(nodes, "")
+
+	vars := make([]string, 0, len(seen))
+	for v := range seen {
+		vars = append(vars, v)
+	}
+	sort.Strings(vars)
+	return vars, nil
+}
+
+type mustacheNodeKind int
+
+const (
+	nodeText mustacheNodeKind = iota
+	nodeVariable
+	nodeUnescaped
+	nodeSection
+	nodeInverted
+)
+
+type mustacheNode struct {
+	kind     mustacheNodeKind
+	key      string
+	text     string
+	children []mustacheNode
+}
+
+// renderMustache parses and renders tpl against model in one pass.
+func renderMustache(tpl string, model map[string]interface{}) (string, error) {
+	nodes, _, err := parseMustache(tpl)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	renderNodes(&b, nodes, model)
+	return b.String(), nil
+}
+
+// parseMustache parses tpl into a tree of mustacheNodes, stopping early
+// (and returning the unconsumed remainder) when it hits a closing tag, so
+// that section parsing can recurse.
+func parseMustache(tpl string) ([]mustacheNode, string, error) {
+	var nodes []mustacheNode
+	rest := tpl
+
+	for {
+		start := strings.Index(rest, "{{")
+		if start == -1 {
+			if rest != "" {
+				nodes = append(nodes, mustacheNode{kind: nodeText, text: rest})
+			}
+			return nodes, "", nil
+		}
+
+		if start > 0 {
+			nodes = append(nodes, mustacheNode{kind: nodeText, text: rest[:start]})
+		}
+		rest = rest[start:]
+
+		if strings.HasPrefix(rest, "{{{") {
+			end := strings.Index(rest, "}}}")
+			if end == -1 {
+				return nil, "", fmt.Errorf("unclosed {{{ tag in template")
+			}
+			key := strings.TrimSpace(rest[3:end])
+			nodes = append(nodes, mustacheNode{kind: nodeUnescaped, key: key})
+			rest = rest[end+3:]
+			continue
+		}
+
+		end := strings.Index(rest, "}}")
+		if end == -1 {
+			return nil, "", fmt.Errorf("unclosed {{ tag in template")
+		}
+		tag := strings.TrimSpace(rest[2:end])
+		rest = rest[end+2:]
+
+		switch {
+		case strings.HasPrefix(tag, "#"):
+			key := strings.TrimSpace(tag[1:])
+			children, remainder, err := parseMustache(rest)
+			if err != nil {
+				return nil, "", err
+			}
+			nodes = append(nodes, mustacheNode{kind: nodeSection, key: key, children: children})
+			rest = remainder
+
+		case strings.HasPrefix(tag, "^"):
+			key := strings.TrimSpace(tag[1:])
+			children, remainder, err := parseMustache(rest)
+			if err != nil {
+				return nil, "", err
+			}
+			nodes = append(nodes, mustacheNode{kind: nodeInverted, key: key, children: children})
+			rest = remainder
+
+		case strings.HasPrefix(tag, "/"):
+			return nodes, rest, nil
+
+		default:
+			nodes = append(nodes, mustacheNode{kind: nodeVariable, key: tag})
+		}
+	}
+}
+
+// renderNodes renders nodes against model (or, inside a section iterating
+// over a slice of maps, against the current element merged with model).
+func renderNodes(b *strings.Builder, nodes []mustacheNode, model map[string]interface{}) {
+	for _, n := range nodes {
+		switch n.kind {
+		case nodeText:
+			b.WriteString(n.text)
+
+		case nodeVariable:
+			b.WriteString(escapeHTML(fmt.Sprint(lookup(model, n.key))))
+
+		case nodeUnescaped:
+			b.WriteString(fmt.Sprint(lookup(model, n.key)))
+
+		case nodeSection:
+			renderSection(b, n, model)
+
+		case nodeInverted:
+			if !truthy(lookup(model, n.key)) {
+				renderNodes(b, n.children, model)
+			}
+		}
+	}
+}
+
+// renderSection renders a {{#key}}...{{/key}} block, iterating if the
+// looked-up value is a non-empty slice, rendering once if it's a truthy
+// scalar or map, and rendering nothing otherwise.
+func renderSection(b *strings.Builder, n mustacheNode, model map[string]interface{}) {
+	value := lookup(model, n.key)
+
+	switch v := value.(type) {
+	case []map[string]interface{}:
+		for _, item := range v {
+			renderNodes(b, n.children, mergeModel(model, item))
+		}
+	case []interface{}:
+		for _, item := range v {
+			if m, ok := item.(map[string]interface{}); ok {
+				renderNodes(b, n.children, mergeModel(model, m))
+			} else {
+				renderNodes(b, n.children, model)
+			}
+		}
+	case map[string]interface{}:
+		if len(v) > 0 {
+			renderNodes(b, n.children, mergeModel(model, v))
+		}
+	default:
+		if rv := reflect.ValueOf(value); rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+			for i := 0; i < rv.Len(); i++ {
+				renderNodes(b, n.children, model)
+			}
+			return
+		}
+		if truthy(value) {
+			renderNodes(b, n.children, model)
+		}
+	}
+}
+
+// mergeModel returns a model with item's keys shadowing outer's, so nested
+// section fields can still fall back to outer variables.
+func mergeModel(outer map[string]interface{}, item map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(outer)+len(item))
+	for k, v := range outer {
+		merged[k] = v
+	}
+	for k, v := range item {
+		merged[k] = v
+	}
+	return merged
+}
+
+// truthy mirrors Mustachio's notion of a truthy section value: present,
+// non-nil, non-zero, non-empty.
+func truthy(value interface{}) bool {
+	if value == nil {
+		return false
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Bool:
+		return rv.Bool()
+	case reflect.String:
+		return rv.Len() > 0
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return rv.Len() > 0
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return !rv.IsZero()
+	case reflect.Ptr, reflect.Interface:
+		return !rv.IsNil()
+	default:
+		return true
+	}
+}
+
+// lookup resolves a dotted path (e.g. "user.name") against model, walking
+// into nested maps and, via reflection, exported struct fields. It returns
+// "" if any segment of the path is missing.
+func lookup(model map[string]interface{}, path string) interface{} {
+	var current interface{} = model
+	for _, segment := range strings.Split(path, ".") {
+		switch v := current.(type) {
+		case map[string]interface{}:
+			current = v[segment]
+		default:
+			rv := reflect.ValueOf(current)
+			if rv.Kind() == reflect.Ptr {
+				rv = rv.Elem()
+			}
+			if !rv.IsValid() || rv.Kind() != reflect.Struct {
+				return ""
+			}
+			field := rv.FieldByName(segment)
+			if !field.IsValid() {
+				return ""
+			}
+			current = field.Interface()
+		}
+		if current == nil {
+			return ""
+		}
+	}
+	return current
+}
+
+// escapeHTML mirrors Mustachio's escaping for {{variable}} (as opposed to
+// {{{unescaped}}}) interpolation.
+func escapeHTML(s string) string {
+	replacer := strings.NewReplacer(
+		"&", "&amp;",
+		"<", "&lt;",
+		">", "&gt;",
+		`"`, "&quot;",
+		"'", "&#39;",
+	)
+	return replacer.Replace(s)
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestRenderTemplateVariable(t *testing.T) {
+	template := Template{
+		Subject:  "Hello {{name}}",
+		HtmlBody: "<p>Hi {{name}}, welcome to {{company}}.</p>",
+		TextBody: "Hi {{name}}",
+	}
+	model := map[string]interface{}{"name": "Alice", "company": "Acme"}
+
+	subject, html, text, err := RenderTemplate(template, model)
+	if err != nil {
+		t.Fatalf("RenderTemplate returned error: %v", err)
+	}
+	if subject != "Hello Alice" {
+		t.Errorf("subject = %q, want %q", subject, "Hello Alice")
+	}
+	if html != "<p>Hi Alice, welcome to Acme.</p>" {
+		t.Errorf("html = %q", html)
+	}
+	if text != "Hi Alice" {
+		t.Errorf("text = %q", text)
+	}
+}
+
+func TestRenderTemplateEscaping(t *testing.T) {
+	template := Template{HtmlBody: "{{unsafe}} / {{{unsafe}}}"}
+	model := map[string]interface{}{"unsafe": "<b>&"}
+
+	_, html, _, err := RenderTemplate(template, model)
+	if err != nil {
+		t.Fatalf("RenderTemplate returned error: %v", err)
+	}
+	want := "&lt;b&gt;&amp; / <b>&"
+	if html != want {
+		t.Errorf("html = %q, want %q", html, want)
+	}
+}
+
+func TestRenderTemplateSectionIteratesSlice(t *testing.T) {
+	template := Template{HtmlBody: "{{#items}}[{{name}}]{{/items}}"}
+	model := map[string]interface{}{
+		"items": []map[string]interface{}{
+			{"name": "a"},
+			{"name": "b"},
+		},
+	}
+
+	_, html, _, err := RenderTemplate(template, model)
+	if err != nil {
+		t.Fatalf("RenderTemplate returned error: %v", err)
+	}
+	if html != "[a][b]" {
+		t.Errorf("html = %q, want %q", html, "[a][b]")
+	}
+}
+
+func TestRenderTemplateSectionPushesMapContext(t *testing.T) {
+	// A {{#section}} over a single map[string]interface{} must render its
+	// children against that map, not the outer model.
+	template := Template{HtmlBody: "{{#user}}{{name}}{{/user}}"}
+	model := map[string]interface{}{
+		"user": map[string]interface{}{"name": "Alice"},
+		"name": "outer-should-not-be-used",
+	}
+
+	_, html, _, err := RenderTemplate(template, model)
+	if err != nil {
+		t.Fatalf("RenderTemplate returned error: %v", err)
+	}
+	if html != "Alice" {
+		t.Errorf("html = %q, want %q", html, "Alice")
+	}
+}
+
+func TestRenderTemplateInvertedSection(t *testing.T) {
+	template := Template{HtmlBody: "{{^items}}empty{{/items}}"}
+
+	_, htmlEmpty, _, err := RenderTemplate(template, map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("RenderTemplate returned error: %v", err)
+	}
+	if htmlEmpty != "empty" {
+		t.Errorf("html = %q, want %q", htmlEmpty, "empty")
+	}
+
+	_, htmlNonEmpty, _, err := RenderTemplate(template, map[string]interface{}{
+		"items": []map[string]interface{}{{"name": "a"}},
+	})
+	if err != nil {
+		t.Fatalf("RenderTemplate returned error: %v", err)
+	}
+	if htmlNonEmpty != "" {
+		t.Errorf("html = %q, want empty string", htmlNonEmpty)
+	}
+}
+
+func TestRenderTemplateDottedPath(t *testing.T) {
+	type Address struct {
+		City string
+	}
+	type User struct {
+		Address Address
+	}
+	template := Template{HtmlBody: "{{user.Address.City}}"}
+	model := map[string]interface{}{
+		"user": User{Address: Address{City: "Paris"}},
+	}
+
+	_, html, _, err := RenderTemplate(template, model)
+	if err != nil {
+		t.Fatalf("RenderTemplate returned error: %v", err)
+	}
+	if html != "Paris" {
+		t.Errorf("html = %q, want %q", html, "Paris")
+	}
+}
+
+func TestTemplateModelSchema(t *testing.T) {
+	body := "{{subject}} {{#items}}{{name}}{{/items}} {{^empty}}x{{/empty}}"
+
+	vars, err := TemplateModelSchema(body)
+	if err != nil {
+		t.Fatalf("TemplateModelSchema returned error: %v", err)
+	}
+
+	want := []string{"empty", "items", "items.name", "subject"}
+	sort.Strings(want)
+	if !reflect.DeepEqual(vars, want) {
+		t.Errorf("vars = %v, want %v", vars, want)
+	}
+}
@@ -0,0 +1,236 @@
+package templatesync
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+)
+
+// remoteTemplate is the wire shape of a Postmark template. It's a
+// self-contained copy of the root package's Template struct: templatesync
+// talks to the Postmark API directly rather than importing package main,
+// since a Go "main" package can't be imported as a library.
+type remoteTemplate struct {
+	TemplateID     int64  `json:"templateID,omitempty"`
+	Name           string `json:"name"`
+	Subject        string `json:"subject"`
+	HtmlBody       string `json:"htmlBody"`
+	TextBody       string `json:"textBody"`
+	Alias          string `json:"alias,omitempty"`
+	Active         bool   `json:"active,omitempty"`
+	TemplateType   string `json:"templateType,omitempty"`
+	LayoutTemplate string `json:"layoutTemplate,omitempty"`
+}
+
+type templatesResponse struct {
+	Templates  []remoteTemplate `json:"templates"`
+	TotalCount int              `json:"totalCount"`
+}
+
+type postmarkResponse struct {
+	TemplateID int64  `json:"templateID"`
+	ErrorCode  int    `json:"errorCode"`
+	Message    string `json:"message"`
+}
+
+// listPageSize is how many templates are requested per page when walking
+// listTemplates' pagination.
+const listPageSize = 100
+
+// apiBaseURL is the root of the Postmark API. It's a var rather than a
+// const so tests can point it at an httptest.Server.
+var apiBaseURL = "https://api.postmarkapp.com"
+
+// listAllTemplates pages through the templates endpoint until it has seen
+// every template on the server.
+func listAllTemplates(apiToken string) ([]remoteTemplate, error) {
+	var all []remoteTemplate
+	for offset := 0; ; offset += listPageSize {
+		page, err := listTemplates(apiToken, offset, listPageSize)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page...)
+		if len(page) < listPageSize {
+			return all, nil
+		}
+	}
+}
+
+func listTemplates(apiToken string, offset, count int) ([]remoteTemplate, error) {
+	url := fmt.Sprintf("%s/templates?offset=%d&count=%d", apiBaseURL, offset, count)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-Postmark-Server-Token", apiToken)
+
+	resp, err := (&http.Client{}).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := ioutil.ReadAll(resp.Body)
+		return nil, fmt.Errorf("unexpected status code: %d, response: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var listResponse templatesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&listResponse); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return listResponse.Templates, nil
+}
+
+func getTemplate(apiToken string, templateID int64) (*remoteTemplate, error) {
+	url := fmt.Sprintf("%s/templates/%d", apiBaseURL, templateID)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-Postmark-Server-Token", apiToken)
+
+	resp, err := (&http.Client{}).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := ioutil.ReadAll(resp.Body)
+		return nil, fmt.Errorf("unexpected status code: %d, response: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var template remoteTemplate
+	if err := json.NewDecoder(resp.Body).Decode(&template); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &template, nil
+}
+
+func createTemplate(apiToken string, template remoteTemplate) (int64, error) {
+	jsonData, err := json.Marshal(template)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal template: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", apiBaseURL+"/templates", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Postmark-Server-Token", apiToken)
+
+	resp, err := (&http.Client{}).Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := ioutil.ReadAll(resp.Body)
+		return 0, fmt.Errorf("unexpected status code: %d, response: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var result postmarkResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if result.ErrorCode != 0 {
+		return 0, fmt.Errorf("failed to create template: %s", result.Message)
+	}
+	return result.TemplateID, nil
+}
+
+func updateTemplate(apiToken string, template remoteTemplate) error {
+	jsonData, err := json.Marshal(template)
+	if err != nil {
+		return fmt.Errorf("failed to marshal template: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/templates/%d", apiBaseURL, template.TemplateID)
+	req, err := http.NewRequest("PUT", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Postmark-Server-Token", apiToken)
+
+	resp, err := (&http.Client{}).Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected status code: %d, response: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var result postmarkResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	if result.ErrorCode != 0 {
+		return fmt.Errorf("failed to update template: %s", result.Message)
+	}
+	return nil
+}
+
+func deleteTemplate(apiToken string, templateID int64) error {
+	url := fmt.Sprintf("%s/templates/%d", apiBaseURL, templateID)
+	req, err := http.NewRequest("DELETE", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-Postmark-Server-Token", apiToken)
+
+	resp, err := (&http.Client{}).Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected status code: %d, response: %s", resp.StatusCode, string(bodyBytes))
+	}
+	return nil
+}
+
+func validateTemplate(apiToken string, template remoteTemplate) error {
+	jsonData, err := json.Marshal(template)
+	if err != nil {
+		return fmt.Errorf("failed to marshal template: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", apiBaseURL+"/templates/validate", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Postmark-Server-Token", apiToken)
+
+	resp, err := (&http.Client{}).Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected status code: %d, response: %s", resp.StatusCode, string(bodyBytes))
+	}
+	return nil
+}
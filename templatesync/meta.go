@@ -0,0 +1,89 @@
+// Package templatesync treats a directory on disk as the source of truth
+// for a Postmark server's templates, so they can be versioned and reviewed
+// like any other code (a GitOps workflow). Each template is stored as a
+// folder named after its alias containing meta.json, content.html, and
+// content.txt.
+package templatesync
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	metaFileName = "meta.json"
+	htmlFileName = "content.html"
+	textFileName = "content.txt"
+)
+
+// TemplateMeta is the on-disk representation of a template's metadata. It
+// mirrors the Postmark API's template fields but omits the body fields,
+// which are stored separately as content.html/content.txt so they diff
+// cleanly in git.
+type TemplateMeta struct {
+	TemplateID     int64  `json:"templateID,omitempty"`
+	Name           string `json:"name"`
+	Subject        string `json:"subject"`
+	Alias          string `json:"alias"`
+	Active         bool   `json:"active,omitempty"`
+	TemplateType   string `json:"templateType,omitempty"`
+	LayoutTemplate string `json:"layoutTemplate,omitempty"`
+}
+
+// localTemplate is a template as read from dir: its metadata plus the
+// content files sitting alongside meta.json.
+type localTemplate struct {
+	meta     TemplateMeta
+	htmlBody string
+	textBody string
+}
+
+// toRemote converts a local template into the wire shape the API expects.
+func (t localTemplate) toRemote() remoteTemplate {
+	return remoteTemplate{
+		TemplateID:     t.meta.TemplateID,
+		Name:           t.meta.Name,
+		Subject:        t.meta.Subject,
+		HtmlBody:       t.htmlBody,
+		TextBody:       t.textBody,
+		Alias:          t.meta.Alias,
+		Active:         t.meta.Active,
+		TemplateType:   t.meta.TemplateType,
+		LayoutTemplate: t.meta.LayoutTemplate,
+	}
+}
+
+// fromRemote converts an API template into its local representation.
+func fromRemote(template remoteTemplate) localTemplate {
+	return localTemplate{
+		meta: TemplateMeta{
+			TemplateID:     template.TemplateID,
+			Name:           template.Name,
+			Subject:        template.Subject,
+			Alias:          template.Alias,
+			Active:         template.Active,
+			TemplateType:   template.TemplateType,
+			LayoutTemplate: template.LayoutTemplate,
+		},
+		htmlBody: template.HtmlBody,
+		textBody: template.TextBody,
+	}
+}
+
+// contentHash returns a stable hash of everything about t that Push cares
+// about changing, so a diff against remote state can skip unchanged
+// templates without relying on Postmark to tell us what's dirty.
+func (t localTemplate) contentHash() string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00%s\x00%t\x00%s\x00%s",
+		t.meta.Name, t.meta.Subject, t.htmlBody, t.textBody, t.meta.Active,
+		t.meta.TemplateType, t.meta.LayoutTemplate)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// marshalMeta renders a TemplateMeta as indented JSON for writing to disk.
+func marshalMeta(meta TemplateMeta) ([]byte, error) {
+	return json.MarshalIndent(meta, "", "  ")
+}
@@ -0,0 +1,41 @@
+package templatesync
+
+import "testing"
+
+func TestLocalTemplateContentHash(t *testing.T) {
+	a := localTemplate{
+		meta:     TemplateMeta{Alias: "welcome", Name: "Welcome", Subject: "Hi"},
+		htmlBody: "<p>Hi</p>",
+		textBody: "Hi",
+	}
+	b := a
+	if a.contentHash() != b.contentHash() {
+		t.Error("identical templates produced different hashes")
+	}
+
+	b.htmlBody = "<p>Hi there</p>"
+	if a.contentHash() == b.contentHash() {
+		t.Error("changing htmlBody didn't change the hash")
+	}
+}
+
+func TestRemoteRoundTrip(t *testing.T) {
+	local := localTemplate{
+		meta: TemplateMeta{
+			TemplateID:     7,
+			Name:           "Welcome",
+			Subject:        "Hi {{name}}",
+			Alias:          "welcome",
+			Active:         true,
+			TemplateType:   "Standard",
+			LayoutTemplate: "main-layout",
+		},
+		htmlBody: "<p>Hi {{name}}</p>",
+		textBody: "Hi {{name}}",
+	}
+
+	roundTripped := fromRemote(local.toRemote())
+	if roundTripped != local {
+		t.Errorf("fromRemote(toRemote(local)) = %+v, want %+v", roundTripped, local)
+	}
+}
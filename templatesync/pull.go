@@ -0,0 +1,61 @@
+package templatesync
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// PullTemplates fetches every template from the Postmark server identified
+// by apiToken and writes each one into dir/<alias>/{meta.json,content.html,content.txt},
+// overwriting whatever is already there.
+func PullTemplates(apiToken string, dir string) error {
+	summaries, err := listAllTemplates(apiToken)
+	if err != nil {
+		return fmt.Errorf("failed to list templates: %w", err)
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+
+	for _, summary := range summaries {
+		full, err := getTemplate(apiToken, summary.TemplateID)
+		if err != nil {
+			return fmt.Errorf("failed to fetch template %d: %w", summary.TemplateID, err)
+		}
+
+		if err := writeLocalTemplate(dir, fromRemote(*full)); err != nil {
+			return fmt.Errorf("failed to write template %q: %w", full.Alias, err)
+		}
+	}
+
+	return nil
+}
+
+// writeLocalTemplate writes t's meta.json, content.html, and content.txt
+// into dir/<alias>, creating the folder if necessary. Templates without an
+// alias are keyed by their numeric TemplateID instead.
+func writeLocalTemplate(dir string, t localTemplate) error {
+	folderName := t.meta.Alias
+	if folderName == "" {
+		folderName = fmt.Sprintf("%d", t.meta.TemplateID)
+	}
+	templateDir := filepath.Join(dir, folderName)
+
+	if err := os.MkdirAll(templateDir, 0o755); err != nil {
+		return err
+	}
+
+	metaBytes, err := marshalMeta(t.meta)
+	if err != nil {
+		return fmt.Errorf("failed to marshal meta.json: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(templateDir, metaFileName), metaBytes, 0o644); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(templateDir, htmlFileName), []byte(t.htmlBody), 0o644); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(templateDir, textFileName), []byte(t.textBody), 0o644)
+}
@@ -0,0 +1,154 @@
+package templatesync
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// PushOptions configures PushTemplates.
+type PushOptions struct {
+	// DryRun prints the planned changes without creating, updating, or
+	// deleting anything. Remote templates are still read, and still
+	// validated when Validate is set.
+	DryRun bool
+	// Prune deletes remote templates whose alias has no matching local
+	// folder. Without it, templates missing locally are left alone.
+	Prune bool
+	// Validate runs each changed template through /templates/validate
+	// before pushing it, so broken Mustachio is caught in CI.
+	Validate bool
+}
+
+// PushResult summarizes what PushTemplates did (or, in dry-run mode, would
+// do), keyed by template alias.
+type PushResult struct {
+	Created []string
+	Updated []string
+	Deleted []string
+}
+
+// PushTemplates reads the templates in dir and reconciles the Postmark
+// server identified by apiToken to match: new aliases are created, changed
+// aliases are updated, and unchanged aliases are left alone. Aliases
+// missing locally are only deleted if opts.Prune is set.
+func PushTemplates(apiToken string, dir string, opts PushOptions) (PushResult, error) {
+	var result PushResult
+
+	locals, err := readLocalTemplates(dir)
+	if err != nil {
+		return result, fmt.Errorf("failed to read local templates: %w", err)
+	}
+
+	remotes, err := listAllTemplates(apiToken)
+	if err != nil {
+		return result, fmt.Errorf("failed to list templates: %w", err)
+	}
+	remoteByAlias := make(map[string]remoteTemplate, len(remotes))
+	for _, r := range remotes {
+		remoteByAlias[r.Alias] = r
+	}
+
+	for alias, local := range locals {
+		remote, exists := remoteByAlias[alias]
+		if !exists {
+			if opts.Validate {
+				if err := validateTemplate(apiToken, local.toRemote()); err != nil {
+					return result, fmt.Errorf("template %q failed validation: %w", alias, err)
+				}
+			}
+			if opts.DryRun {
+				fmt.Printf("would create template %q\n", alias)
+			} else if _, err := createTemplate(apiToken, local.toRemote()); err != nil {
+				return result, fmt.Errorf("failed to create template %q: %w", alias, err)
+			}
+			result.Created = append(result.Created, alias)
+			continue
+		}
+
+		full, err := getTemplate(apiToken, remote.TemplateID)
+		if err != nil {
+			return result, fmt.Errorf("failed to fetch template %q: %w", alias, err)
+		}
+		if fromRemote(*full).contentHash() == local.contentHash() {
+			continue
+		}
+
+		if opts.Validate {
+			if err := validateTemplate(apiToken, local.toRemote()); err != nil {
+				return result, fmt.Errorf("template %q failed validation: %w", alias, err)
+			}
+		}
+
+		if opts.DryRun {
+			fmt.Printf("would update template %q\n", alias)
+		} else {
+			toUpdate := local.toRemote()
+			toUpdate.TemplateID = remote.TemplateID
+			if err := updateTemplate(apiToken, toUpdate); err != nil {
+				return result, fmt.Errorf("failed to update template %q: %w", alias, err)
+			}
+		}
+		result.Updated = append(result.Updated, alias)
+	}
+
+	if opts.Prune {
+		for alias, remote := range remoteByAlias {
+			if _, exists := locals[alias]; exists {
+				continue
+			}
+			if opts.DryRun {
+				fmt.Printf("would delete template %q\n", alias)
+			} else if err := deleteTemplate(apiToken, remote.TemplateID); err != nil {
+				return result, fmt.Errorf("failed to delete template %q: %w", alias, err)
+			}
+			result.Deleted = append(result.Deleted, alias)
+		}
+	}
+
+	return result, nil
+}
+
+// readLocalTemplates reads every <alias>/{meta.json,content.html,content.txt}
+// folder under dir, keyed by alias.
+func readLocalTemplates(dir string) (map[string]localTemplate, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	locals := make(map[string]localTemplate, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		templateDir := filepath.Join(dir, entry.Name())
+
+		metaBytes, err := os.ReadFile(filepath.Join(templateDir, metaFileName))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", metaFileName, err)
+		}
+		var meta TemplateMeta
+		if err := json.Unmarshal(metaBytes, &meta); err != nil {
+			return nil, fmt.Errorf("failed to parse %s/%s: %w", entry.Name(), metaFileName, err)
+		}
+
+		htmlBody, err := os.ReadFile(filepath.Join(templateDir, htmlFileName))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", htmlFileName, err)
+		}
+		textBody, err := os.ReadFile(filepath.Join(templateDir, textFileName))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", textFileName, err)
+		}
+
+		locals[meta.Alias] = localTemplate{
+			meta:     meta,
+			htmlBody: string(htmlBody),
+			textBody: string(textBody),
+		}
+	}
+
+	return locals, nil
+}
@@ -0,0 +1,155 @@
+package templatesync
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeTestTemplate writes a local template folder the way readLocalTemplates expects.
+func writeTestTemplate(t *testing.T, dir string, local localTemplate) {
+	t.Helper()
+	templateDir := filepath.Join(dir, local.meta.Alias)
+	if err := os.MkdirAll(templateDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	metaBytes, err := marshalMeta(local.meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(templateDir, metaFileName), metaBytes, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(templateDir, htmlFileName), []byte(local.htmlBody), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(templateDir, textFileName), []byte(local.textBody), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPushTemplatesDryRunDoesNotMutate(t *testing.T) {
+	unchanged := localTemplate{
+		meta:     TemplateMeta{TemplateID: 1, Alias: "unchanged", Name: "Unchanged", Subject: "Hi"},
+		htmlBody: "<p>Hi</p>",
+		textBody: "Hi",
+	}
+	changed := localTemplate{
+		meta:     TemplateMeta{TemplateID: 2, Alias: "changed", Name: "Changed", Subject: "Hi"},
+		htmlBody: "<p>New content</p>",
+		textBody: "New content",
+	}
+	remoteChanged := changed
+	remoteChanged.htmlBody = "<p>Old content</p>"
+	remoteChanged.textBody = "Old content"
+
+	remotes := map[int64]remoteTemplate{
+		1: unchanged.toRemote(),
+		2: remoteChanged.toRemote(),
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/templates":
+			var list []remoteTemplate
+			for _, rt := range remotes {
+				list = append(list, rt)
+			}
+			json.NewEncoder(w).Encode(templatesResponse{Templates: list, TotalCount: len(list)})
+		case r.Method == http.MethodGet:
+			var id int64
+			if _, err := fmt.Sscanf(r.URL.Path, "/templates/%d", &id); err != nil {
+				t.Fatalf("unexpected GET path %q", r.URL.Path)
+			}
+			json.NewEncoder(w).Encode(remotes[id])
+		default:
+			t.Fatalf("dry run made a mutating request: %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	original := apiBaseURL
+	apiBaseURL = server.URL
+	defer func() { apiBaseURL = original }()
+
+	dir := t.TempDir()
+	writeTestTemplate(t, dir, unchanged)
+	writeTestTemplate(t, dir, changed)
+
+	result, err := PushTemplates("test-token", dir, PushOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("PushTemplates returned error: %v", err)
+	}
+	if len(result.Created) != 0 || len(result.Deleted) != 0 {
+		t.Errorf("result = %+v, want no creates or deletes", result)
+	}
+	if len(result.Updated) != 1 || result.Updated[0] != "changed" {
+		t.Errorf("result.Updated = %v, want [changed]", result.Updated)
+	}
+}
+
+func TestPushTemplatesValidatesOnlyChangedTemplates(t *testing.T) {
+	unchanged := localTemplate{
+		meta:     TemplateMeta{TemplateID: 1, Alias: "unchanged", Name: "Unchanged", Subject: "Hi"},
+		htmlBody: "<p>Hi</p>",
+		textBody: "Hi",
+	}
+	changed := localTemplate{
+		meta:     TemplateMeta{TemplateID: 2, Alias: "changed", Name: "Changed", Subject: "Hi"},
+		htmlBody: "<p>New content</p>",
+		textBody: "New content",
+	}
+	remoteChanged := changed
+	remoteChanged.htmlBody = "<p>Old content</p>"
+	remoteChanged.textBody = "Old content"
+
+	remotes := map[int64]remoteTemplate{
+		1: unchanged.toRemote(),
+		2: remoteChanged.toRemote(),
+	}
+
+	var validated []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/templates":
+			var list []remoteTemplate
+			for _, rt := range remotes {
+				list = append(list, rt)
+			}
+			json.NewEncoder(w).Encode(templatesResponse{Templates: list, TotalCount: len(list)})
+		case r.Method == http.MethodGet:
+			var id int64
+			if _, err := fmt.Sscanf(r.URL.Path, "/templates/%d", &id); err != nil {
+				t.Fatalf("unexpected GET path %q", r.URL.Path)
+			}
+			json.NewEncoder(w).Encode(remotes[id])
+		case r.Method == http.MethodPost && r.URL.Path == "/templates/validate":
+			var tmpl remoteTemplate
+			json.NewDecoder(r.Body).Decode(&tmpl)
+			validated = append(validated, tmpl.Alias)
+			json.NewEncoder(w).Encode(map[string]interface{}{})
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	original := apiBaseURL
+	apiBaseURL = server.URL
+	defer func() { apiBaseURL = original }()
+
+	dir := t.TempDir()
+	writeTestTemplate(t, dir, unchanged)
+	writeTestTemplate(t, dir, changed)
+
+	if _, err := PushTemplates("test-token", dir, PushOptions{DryRun: true, Validate: true}); err != nil {
+		t.Fatalf("PushTemplates returned error: %v", err)
+	}
+	if len(validated) != 1 || validated[0] != "changed" {
+		t.Errorf("validated = %v, want [changed] (unchanged templates shouldn't be validated)", validated)
+	}
+}